@@ -2,10 +2,9 @@ package renderer
 
 import (
 	"bufio"
-	"bytes"
 	"fmt"
 	"io"
-	"strconv"
+	"strings"
 )
 
 // Server-sent events
@@ -13,12 +12,30 @@ import (
 
 type SSEvent struct {
 	Event string
-	Data  io.Reader
+	Data  []byte
 	ID    string
 	Retry uint
 }
 
+// SSEEncodeError reports that an SSEvent field contained bytes the SSE
+// wire format can't represent, such as a newline in Event or a NUL in ID.
+type SSEEncodeError struct {
+	Field string
+	Value string
+}
+
+func (e *SSEEncodeError) Error() string {
+	return fmt.Sprintf("renderer: sse: %s value %q is not valid for the SSE wire format", e.Field, e.Value)
+}
+
 func SSEventEncode(writer io.Writer, event SSEvent) error {
+	if strings.ContainsAny(event.Event, "\r\n") {
+		return &SSEEncodeError{Field: "event", Value: event.Event}
+	}
+	if strings.ContainsAny(event.ID, "\r\n\x00") {
+		return &SSEEncodeError{Field: "id", Value: event.ID}
+	}
+
 	buf := bufio.NewWriter(writer)
 	if len(event.Event) > 0 {
 		_, err := fmt.Fprintf(buf, "event: %s\n", event.Event)
@@ -39,97 +56,64 @@ func SSEventEncode(writer io.Writer, event SSEvent) error {
 		}
 	}
 
-	_, _ = buf.WriteString("data: ")
-	if _, err := io.Copy(buf, event.Data); err != nil {
+	if err := writeSSEData(buf, event.Data); err != nil {
 		return err
 	}
-	_, _ = buf.WriteString("\n\n")
 
 	return buf.Flush()
 }
 
-func SSEventDecode(reader io.Reader) ([]SSEvent, error) {
-	raw, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, err
+// writeSSEData writes event.Data as one or more "data: " lines, emitting a
+// fresh prefix after every line break and normalizing a bare \r (as well
+// as \r\n) to \n, so a payload containing embedded newlines round-trips
+// through SSEventDecode instead of silently truncating the event.
+func writeSSEData(buf *bufio.Writer, data []byte) error {
+	if _, err := buf.WriteString("data: "); err != nil {
+		return err
 	}
 
-	// Strip UTF-8 BOM if present (per SSE spec)
-	raw = bytes.TrimPrefix(raw, []byte{0xEF, 0xBB, 0xBF})
-
-	// Split into lines, handling CRLF, LF, and CR
-	// Replace CRLF with LF first, then CR with LF
-	raw = bytes.ReplaceAll(raw, []byte{'\r', '\n'}, []byte{'\n'})
-	raw = bytes.ReplaceAll(raw, []byte{'\r'}, []byte{'\n'})
-	lines := bytes.Split(raw, []byte{'\n'})
-
-	var dataLines [][]byte
-	var event SSEvent
-	var events []SSEvent
-
-	for _, line := range lines {
-		if len(line) == 0 {
-			// Empty line marks the end of an event
-			if len(dataLines) == 0 && event.Event == "" {
-				continue
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '\n':
+			if _, err := buf.WriteString("\ndata: "); err != nil {
+				return err
 			}
-
-			// Combine data lines according to SSE spec
-			// Each data field appends value + newline, but the last newline should be removed
-			if len(dataLines) > 0 {
-				data := bytes.Join(dataLines, []byte{'\n'})
-				event.Data = bytes.NewReader(data)
+		case '\r':
+			if _, err := buf.WriteString("\ndata: "); err != nil {
+				return err
 			}
-
-			// Set default event type if not specified
-			if event.Event == "" {
-				event.Event = "message"
+			if i+1 < len(data) && data[i+1] == '\n' {
+				i++
+			}
+		default:
+			if err := buf.WriteByte(data[i]); err != nil {
+				return err
 			}
-
-			events = append(events, event)
-			event = SSEvent{}
-			dataLines = nil
-			continue
 		}
+	}
 
-		// Ignore comment lines
-		if bytes.HasPrefix(line, []byte{':'}) {
-			continue
-		}
+	_, err := buf.WriteString("\n\n")
+	return err
+}
 
-		var field, value []byte
-		index := bytes.IndexRune(line, ':')
-		if index != -1 {
-			field = line[:index]
-			value = line[index+1:]
-			// Remove optional leading space from value (per SSE spec)
-			if len(value) > 0 && value[0] == ' ' {
-				value = value[1:]
-			}
-		} else {
-			field = line
-			value = []byte{}
-		}
+// SSEventDecode reads every event out of reader in a single pass. It is a
+// thin wrapper around SSEventDecoder and buffers the whole stream in
+// memory, so it's only suitable for readers that are already fully
+// available (e.g. a test fixture or a response body read via io.ReadAll).
+// For long-lived connections, where events arrive over time, use
+// SSEventDecoder directly instead.
+func SSEventDecode(reader io.Reader) ([]SSEvent, error) {
+	decoder := NewSSEventDecoder(reader)
 
-		// Process field
-		switch string(field) {
-		case "event":
-			event.Event = string(value)
-		case "id":
-			// Per SSE spec: if the field value does not contain U+0000 NULL
-			if !bytes.Contains(value, []byte{0}) {
-				event.ID = string(value)
-			}
-		case "retry":
-			// Only process if field value consists of only ASCII digits
-			retry, err := strconv.Atoi(string(value))
-			if err == nil && retry >= 0 {
-				event.Retry = uint(retry)
-			}
-		case "data":
-			dataLines = append(dataLines, value)
+	var events []SSEvent
+	for {
+		event, err := decoder.Decode()
+		if err == io.EOF {
+			return events, nil
 		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
 	}
-
-	return events, nil
 }
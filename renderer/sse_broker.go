@@ -0,0 +1,220 @@
+package renderer
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSSEHeartbeat   = 15 * time.Second
+	defaultSSEHistorySize = 100
+)
+
+// SSEBrokerOption configures a SSEBroker.
+type SSEBrokerOption func(*SSEBroker)
+
+// WithSSEHeartbeat sets the interval at which the broker sends a keep-alive
+// comment frame to connected clients, to stop intermediaries from closing
+// idle connections. The default is 15s.
+func WithSSEHeartbeat(d time.Duration) SSEBrokerOption {
+	return func(b *SSEBroker) { b.heartbeat = d }
+}
+
+// WithSSEHistorySize sets how many recently published events the broker
+// keeps in memory so a reconnecting client can replay what it missed via
+// Last-Event-ID. The default is 100.
+func WithSSEHistorySize(n int) SSEBrokerOption {
+	return func(b *SSEBroker) { b.historySize = n }
+}
+
+// SSEBroker is an http.Handler that fans published events out to every
+// connected client over Server-Sent Events. It sends periodic heartbeat
+// comments to keep idle connections alive, and replays recent events to
+// reconnecting clients based on the Last-Event-ID header (or lastEventId
+// query parameter).
+type SSEBroker struct {
+	heartbeat   time.Duration
+	historySize int
+
+	mu        sync.Mutex
+	clients   map[chan SSEvent]struct{}
+	history   []SSEvent
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSSEBroker returns a ready-to-use SSEBroker.
+func NewSSEBroker(opts ...SSEBrokerOption) *SSEBroker {
+	b := &SSEBroker{
+		heartbeat:   defaultSSEHeartbeat,
+		historySize: defaultSSEHistorySize,
+		clients:     make(map[chan SSEvent]struct{}),
+		closed:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Publish sends event to every currently connected client and, if it has
+// an ID, records it in the replay history. A client whose channel buffer
+// is full does not receive the event.
+func (b *SSEBroker) Publish(event SSEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(event.ID) > 0 {
+		b.history = append(b.history, event)
+		if len(b.history) > b.historySize {
+			b.history = b.history[len(b.history)-b.historySize:]
+		}
+	}
+
+	for ch := range b.clients {
+		select {
+		case ch <- event:
+		default:
+			// Slow client; drop the event rather than block the broker.
+		}
+	}
+}
+
+// Subscribe registers a new client channel and returns it, for callers
+// that want to consume events without going through HTTP. The channel is
+// closed by Close.
+func (b *SSEBroker) Subscribe() <-chan SSEvent {
+	return b.subscribe()
+}
+
+// subscribe is like Subscribe but returns the bidirectional channel type.
+func (b *SSEBroker) subscribe() chan SSEvent {
+	ch := make(chan SSEvent, 16)
+	b.mu.Lock()
+	b.clients[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+// unsubscribe removes and closes a client channel returned by
+// subscribeAndReplay or subscribe, unless Close already closed it.
+func (b *SSEBroker) unsubscribe(ch chan SSEvent) {
+	b.mu.Lock()
+	if _, ok := b.clients[ch]; ok {
+		delete(b.clients, ch)
+		close(ch)
+	}
+	b.mu.Unlock()
+}
+
+// subscribeAndReplay registers a new client channel and takes the replay
+// snapshot for lastEventID in the same critical section, so that no event
+// Publish'ed in between can fall through the gap between the snapshot and
+// the subscription (it would otherwise be included in neither).
+func (b *SSEBroker) subscribeAndReplay(lastEventID string) (chan SSEvent, []SSEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	replay := b.replayLocked(lastEventID)
+
+	ch := make(chan SSEvent, 16)
+	b.clients[ch] = struct{}{}
+
+	return ch, replay
+}
+
+// replayLocked returns the history events after lastEventID, or the full
+// history if lastEventID is empty or not found in it. b.mu must be held.
+func (b *SSEBroker) replayLocked(lastEventID string) []SSEvent {
+	if lastEventID != "" {
+		for i, event := range b.history {
+			if event.ID == lastEventID {
+				return append([]SSEvent(nil), b.history[i+1:]...)
+			}
+		}
+	}
+
+	return append([]SSEvent(nil), b.history...)
+}
+
+// Close disconnects every connected client and causes ServeHTTP to refuse
+// new connections.
+func (b *SSEBroker) Close() {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for ch := range b.clients {
+			delete(b.clients, ch)
+			close(ch)
+		}
+	})
+}
+
+// ServeHTTP implements http.Handler. It upgrades the connection to an
+// event stream, replays any events the client missed per Last-Event-ID,
+// and then streams live events until the client disconnects or the broker
+// is closed.
+func (b *SSEBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	select {
+	case <-b.closed:
+		http.Error(w, "stream closed", http.StatusServiceUnavailable)
+		return
+	default:
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+	ch, replay := b.subscribeAndReplay(lastEventID)
+	defer b.unsubscribe(ch)
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	sw := NewSSEventWriter(w, flusher)
+
+	for _, event := range replay {
+		if err := sw.WriteEvent(event); err != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(b.heartbeat)
+	defer ticker.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.closed:
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := sw.WriteEvent(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := sw.WriteComment("ping"); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package renderer
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEBroker_DeliversPublishedEventsToConnectedClients(t *testing.T) {
+	broker := NewSSEBroker(WithSSEHeartbeat(time.Hour))
+	defer broker.Close()
+
+	server := httptest.NewServer(broker)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	require.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	require.Equal(t, "no", resp.Header.Get("X-Accel-Buffering"))
+
+	// Give the handler a moment to subscribe before publishing.
+	time.Sleep(50 * time.Millisecond)
+	broker.Publish(SSEvent{ID: "1", Data: []byte("hello")})
+
+	require.ElementsMatch(t, []string{"id: 1", "data: hello"}, readEventLines(t, resp.Body))
+}
+
+func TestSSEBroker_ReplaysEventsAfterLastEventID(t *testing.T) {
+	broker := NewSSEBroker(WithSSEHeartbeat(time.Hour))
+	defer broker.Close()
+
+	broker.Publish(SSEvent{ID: "1", Data: []byte("one")})
+	broker.Publish(SSEvent{ID: "2", Data: []byte("two")})
+	broker.Publish(SSEvent{ID: "3", Data: []byte("three")})
+
+	server := httptest.NewServer(broker)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for i := 0; i < 2; i++ {
+		lines = append(lines, readEventLinesFrom(t, reader)...)
+	}
+
+	require.Contains(t, lines, "id: 2")
+	require.Contains(t, lines, "data: two")
+	require.Contains(t, lines, "id: 3")
+	require.Contains(t, lines, "data: three")
+	require.NotContains(t, lines, "data: one")
+}
+
+func TestSSEBroker_SendsHeartbeatComments(t *testing.T) {
+	broker := NewSSEBroker(WithSSEHeartbeat(20 * time.Millisecond))
+	defer broker.Close()
+
+	server := httptest.NewServer(broker)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, ": ping\n", line)
+}
+
+func TestSSEBroker_SubscribeAndReplayIsAtomic(t *testing.T) {
+	broker := NewSSEBroker()
+	defer broker.Close()
+
+	broker.Publish(SSEvent{ID: "1", Data: []byte("one")})
+
+	ch, replay := broker.subscribeAndReplay("1")
+	require.Empty(t, replay, "event 1 was already acknowledged via Last-Event-ID")
+
+	// Published right after subscribing; must not fall through the gap
+	// between the replay snapshot and live delivery.
+	broker.Publish(SSEvent{ID: "2", Data: []byte("two")})
+
+	select {
+	case event := <-ch:
+		require.Equal(t, "2", event.ID)
+	case <-time.After(time.Second):
+		t.Fatal("event published right after subscribing was never delivered")
+	}
+}
+
+func TestSSEBroker_CloseDisconnectsSubscribers(t *testing.T) {
+	broker := NewSSEBroker()
+	ch := broker.Subscribe()
+
+	broker.Close()
+
+	_, ok := <-ch
+	require.False(t, ok)
+}
+
+func readEventLines(t *testing.T, r io.Reader) []string {
+	t.Helper()
+	return readEventLinesFrom(t, bufio.NewReader(r))
+}
+
+func readEventLinesFrom(t *testing.T, reader *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		trimmed := strings.TrimRight(line, "\n")
+		if trimmed == "" {
+			return lines
+		}
+		lines = append(lines, trimmed)
+	}
+}
@@ -0,0 +1,187 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultSSEClientRetry    = 3 * time.Second
+	defaultSSEClientMaxRetry = 30 * time.Second
+)
+
+// SSEClientOption configures a SSEClient.
+type SSEClientOption func(*SSEClient)
+
+// WithSSEClientHTTPClient sets the *http.Client used to make requests. The
+// default is http.DefaultClient.
+func WithSSEClientHTTPClient(c *http.Client) SSEClientOption {
+	return func(cl *SSEClient) { cl.httpClient = c }
+}
+
+// WithSSEClientRetry sets the reconnection delay used until the server
+// sends its own retry: value. The default is 3s.
+func WithSSEClientRetry(d time.Duration) SSEClientOption {
+	return func(cl *SSEClient) { cl.retry = d }
+}
+
+// WithSSEClientMaxRetry caps the exponential backoff applied to repeated
+// reconnection failures. The default is 30s.
+func WithSSEClientMaxRetry(d time.Duration) SSEClientOption {
+	return func(cl *SSEClient) { cl.maxRetry = d }
+}
+
+// SSEClient connects to a Server-Sent Events endpoint and reconnects
+// automatically on network errors or a clean stream close, resending the
+// last seen event ID via Last-Event-ID and honoring the server's retry:
+// directive for the reconnection delay.
+type SSEClient struct {
+	httpClient *http.Client
+	url        string
+	retry      time.Duration
+	maxRetry   time.Duration
+
+	events chan SSEvent
+	errs   chan error
+}
+
+// NewSSEClient returns a SSEClient that connects to url.
+func NewSSEClient(url string, opts ...SSEClientOption) *SSEClient {
+	c := &SSEClient{
+		httpClient: http.DefaultClient,
+		url:        url,
+		retry:      defaultSSEClientRetry,
+		maxRetry:   defaultSSEClientMaxRetry,
+		events:     make(chan SSEvent),
+		errs:       make(chan error, 1),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Errors returns the channel reconnection and request errors are reported
+// on. It is closed once the client stops.
+func (c *SSEClient) Errors() <-chan error {
+	return c.errs
+}
+
+// Events connects to the client's URL and streams decoded events on the
+// returned channel, reconnecting automatically until ctx is cancelled.
+// Both the returned channel and the Errors channel are closed once the
+// client stops.
+func (c *SSEClient) Events(ctx context.Context) <-chan SSEvent {
+	go c.run(ctx)
+	return c.events
+}
+
+func (c *SSEClient) run(ctx context.Context) {
+	defer close(c.events)
+	defer close(c.errs)
+
+	lastEventID := ""
+	retry := c.retry
+	failures := 0
+
+	for ctx.Err() == nil {
+		newRetry, err := c.connect(ctx, &lastEventID)
+		if newRetry > 0 {
+			retry = newRetry
+		}
+
+		if err != nil {
+			failures++
+			c.sendError(err)
+		} else {
+			failures = 0
+		}
+
+		delay := retry
+		if failures > 0 {
+			delay = backoffDelay(retry, failures, c.maxRetry)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// connect performs a single GET request and decodes events from the
+// response until it errors or the server closes the stream. It returns
+// the most recent retry: value seen, if any.
+func (c *SSEClient) connect(ctx context.Context, lastEventID *string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastEventID != "" {
+		req.Header.Set("Last-Event-ID", *lastEventID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("renderer: sse client: unexpected status %s", resp.Status)
+	}
+
+	decoder := NewSSEventDecoder(resp.Body)
+	var retry time.Duration
+
+	for {
+		event, err := decoder.Decode()
+		if err == io.EOF {
+			return retry, nil
+		}
+		if err != nil {
+			return retry, err
+		}
+
+		if event.ID != "" {
+			*lastEventID = event.ID
+		}
+		if event.Retry > 0 {
+			retry = time.Duration(event.Retry) * time.Millisecond
+		}
+
+		select {
+		case c.events <- event:
+		case <-ctx.Done():
+			return retry, nil
+		}
+	}
+}
+
+func (c *SSEClient) sendError(err error) {
+	select {
+	case c.errs <- err:
+	default:
+		// Drop the error if nobody's listening, rather than block reconnects.
+	}
+}
+
+// backoffDelay doubles base for every failure beyond the first, capped at max.
+func backoffDelay(base time.Duration, failures int, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
@@ -0,0 +1,109 @@
+package renderer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEClient_ReconnectsAndResendsLastEventID(t *testing.T) {
+	var requests int32
+	lastIDCh := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			require.NoError(t, SSEventEncode(w, SSEvent{ID: "1", Data: []byte("first")}))
+			flusher.Flush()
+		default:
+			lastIDCh <- r.Header.Get("Last-Event-ID")
+			require.NoError(t, SSEventEncode(w, SSEvent{ID: "2", Data: []byte("second")}))
+			flusher.Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	client := NewSSEClient(server.URL, WithSSEClientRetry(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := client.Events(ctx)
+
+	first := <-events
+	require.Equal(t, "first", string(first.Data))
+
+	second := <-events
+	require.Equal(t, "second", string(second.Data))
+	require.Equal(t, "1", <-lastIDCh)
+
+	cancel()
+
+	_, ok := <-events
+	require.False(t, ok)
+}
+
+func TestSSEClient_UsesServerRetryValue(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		if atomic.AddInt32(&requests, 1) == 1 {
+			require.NoError(t, SSEventEncode(w, SSEvent{ID: "1", Data: []byte("hi"), Retry: 5}))
+			flusher.Flush()
+			return
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewSSEClient(server.URL, WithSSEClientRetry(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := client.Events(ctx)
+	<-events
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&requests) >= 2
+	}, time.Second, time.Millisecond, "client should reconnect using the server's retry: value instead of the configured default")
+}
+
+func TestSSEClient_ContextCancelClosesChannels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewSSEClient(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events := client.Events(ctx)
+	errs := client.Errors()
+
+	cancel()
+
+	_, ok := <-events
+	require.False(t, ok)
+	_, ok = <-errs
+	require.False(t, ok)
+}
+
+func TestBackoffDelay(t *testing.T) {
+	require.Equal(t, 100*time.Millisecond, backoffDelay(100*time.Millisecond, 1, time.Second))
+	require.Equal(t, 200*time.Millisecond, backoffDelay(100*time.Millisecond, 2, time.Second))
+	require.Equal(t, 400*time.Millisecond, backoffDelay(100*time.Millisecond, 3, time.Second))
+	require.Equal(t, time.Second, backoffDelay(100*time.Millisecond, 10, time.Second))
+}
@@ -0,0 +1,172 @@
+package renderer
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+)
+
+// utf8BOM is the byte-order mark SSE streams may be prefixed with.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+const (
+	// sseDecoderInitialBufferSize is the starting size of the scanner's
+	// line buffer; it grows up to maxSize as needed.
+	sseDecoderInitialBufferSize = 64 * 1024
+	// defaultSSEDecoderMaxSize is the largest single line (e.g. a data:
+	// field) the decoder accepts by default.
+	defaultSSEDecoderMaxSize = 1024 * 1024
+)
+
+// SSEventDecoderOption configures a SSEventDecoder.
+type SSEventDecoderOption func(*SSEventDecoder)
+
+// WithSSEventDecoderMaxSize sets the largest single line the decoder will
+// accept, in bytes. Decode returns bufio.ErrTooLong if a line exceeds
+// this, which would otherwise permanently end a long-lived stream that
+// happens to emit a data: field larger than the default (e.g. a base64
+// payload or large JSON diff). The default is 1MB.
+func WithSSEventDecoderMaxSize(n int) SSEventDecoderOption {
+	return func(d *SSEventDecoder) { d.maxSize = n }
+}
+
+// SSEventDecoder parses Server-Sent Events from a reader one at a time, so
+// callers can range over events as they arrive instead of waiting for the
+// stream to close. It is safe to use directly on a long-lived response
+// body: unlike SSEventDecode it only holds the fields of the event it is
+// currently decoding, not every event in the stream.
+type SSEventDecoder struct {
+	maxSize int
+
+	scanner *bufio.Scanner
+	readAny bool
+}
+
+// NewSSEventDecoder returns a decoder that reads events from r.
+func NewSSEventDecoder(r io.Reader, opts ...SSEventDecoderOption) *SSEventDecoder {
+	d := &SSEventDecoder{maxSize: defaultSSEDecoderMaxSize}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	initialSize := sseDecoderInitialBufferSize
+	if d.maxSize < initialSize {
+		initialSize = d.maxSize
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, initialSize), d.maxSize)
+	scanner.Split(splitSSELine)
+	d.scanner = scanner
+
+	return d
+}
+
+// Decode reads and returns the next event from the stream. It returns
+// io.EOF once the underlying reader is exhausted.
+func (d *SSEventDecoder) Decode() (SSEvent, error) {
+	var event SSEvent
+	var dataLines [][]byte
+
+	for d.scanner.Scan() {
+		line := d.scanner.Bytes()
+		if !d.readAny {
+			d.readAny = true
+			line = bytes.TrimPrefix(line, utf8BOM)
+		}
+
+		if len(line) == 0 {
+			// Empty line marks the end of an event.
+			if len(dataLines) == 0 && event.Event == "" {
+				continue
+			}
+
+			// Combine data lines according to SSE spec: each data field
+			// appends value + newline, but the last newline is dropped.
+			if len(dataLines) > 0 {
+				event.Data = bytes.Join(dataLines, []byte{'\n'})
+			}
+
+			if event.Event == "" {
+				event.Event = "message"
+			}
+
+			return event, nil
+		}
+
+		// Ignore comment lines.
+		if bytes.HasPrefix(line, []byte{':'}) {
+			continue
+		}
+
+		var field, value []byte
+		if index := bytes.IndexByte(line, ':'); index != -1 {
+			field, value = line[:index], line[index+1:]
+			// Remove optional leading space from value (per SSE spec).
+			if len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+		} else {
+			field = line
+		}
+
+		switch string(field) {
+		case "event":
+			event.Event = string(value)
+		case "id":
+			// Per SSE spec: ignore id values containing U+0000 NULL.
+			if !bytes.Contains(value, []byte{0}) {
+				event.ID = string(value)
+			}
+		case "retry":
+			// Only process if the field value consists of ASCII digits.
+			if len(value) > 0 && len(bytes.TrimLeft(value, "0123456789")) == 0 {
+				if retry, err := strconv.Atoi(string(value)); err == nil {
+					event.Retry = uint(retry)
+				}
+			}
+		case "data":
+			// line is only valid until the next Scan, so copy it before
+			// accumulating it across iterations.
+			dataLines = append(dataLines, append([]byte(nil), value...))
+		}
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return SSEvent{}, err
+	}
+	return SSEvent{}, io.EOF
+}
+
+// splitSSELine is a bufio.SplitFunc that treats CR, LF, and CRLF alike as
+// line terminators, per the SSE spec's line-ending rules.
+func splitSSELine(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	for i, b := range data {
+		switch b {
+		case '\n':
+			return i + 1, data[:i], nil
+		case '\r':
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+				return i + 1, data[:i], nil
+			}
+			if atEOF {
+				return i + 1, data[:i], nil
+			}
+			// Need more data to know whether this CR is part of a CRLF.
+			return 0, nil, nil
+		}
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,98 @@
+package renderer
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEventDecoder_HandlesDataLinesLargerThanDefaultScannerBuffer(t *testing.T) {
+	large := strings.Repeat("x", 128*1024) // larger than bufio.Scanner's 64KB default
+	input := "data: " + large + "\n\n"
+
+	decoder := NewSSEventDecoder(strings.NewReader(input))
+	event, err := decoder.Decode()
+	require.NoError(t, err)
+	require.Equal(t, large, string(event.Data))
+}
+
+func TestSSEventDecoder_WithSSEventDecoderMaxSize(t *testing.T) {
+	input := "data: " + strings.Repeat("x", 100) + "\n\n"
+
+	decoder := NewSSEventDecoder(strings.NewReader(input), WithSSEventDecoderMaxSize(16))
+	_, err := decoder.Decode()
+	require.Error(t, err)
+}
+
+func TestSSEventDecoder_YieldsEventsOneAtATime(t *testing.T) {
+	input := "event: msg1\ndata: first\n\nevent: msg2\ndata: second\n\n"
+	decoder := NewSSEventDecoder(strings.NewReader(input))
+
+	first, err := decoder.Decode()
+	require.NoError(t, err)
+	require.Equal(t, "msg1", first.Event)
+	require.Equal(t, "first", string(first.Data))
+
+	second, err := decoder.Decode()
+	require.NoError(t, err)
+	require.Equal(t, "msg2", second.Event)
+	require.Equal(t, "second", string(second.Data))
+
+	_, err = decoder.Decode()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestSSEventDecoder_EOFOnEmptyStream(t *testing.T) {
+	decoder := NewSSEventDecoder(strings.NewReader(""))
+
+	_, err := decoder.Decode()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestSSEventDecoder_WaitsForEventsOnOpenStream(t *testing.T) {
+	pr, pw := io.Pipe()
+	decoder := NewSSEventDecoder(pr)
+
+	go func() {
+		_, _ = pw.Write([]byte("event: tick\ndata: 1\n\n"))
+	}()
+
+	event, err := decoder.Decode()
+	require.NoError(t, err)
+	require.Equal(t, "tick", event.Event)
+	require.Equal(t, "1", string(event.Data))
+
+	require.NoError(t, pw.Close())
+
+	_, err = decoder.Decode()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestSSEventDecoder_BOMOnlyStrippedOnFirstRead(t *testing.T) {
+	input := "\xEF\xBB\xBFdata: first\n\ndata: \xEF\xBB\xBFsecond\n\n"
+	decoder := NewSSEventDecoder(strings.NewReader(input))
+
+	first, err := decoder.Decode()
+	require.NoError(t, err)
+	require.Equal(t, "first", string(first.Data))
+
+	second, err := decoder.Decode()
+	require.NoError(t, err)
+	require.Equal(t, "\xEF\xBB\xBFsecond", string(second.Data))
+}
+
+func TestSSEventDecoder_DataBufferNotAliased(t *testing.T) {
+	input := "data: one\n\ndata: two\n\n"
+	decoder := NewSSEventDecoder(strings.NewReader(input))
+
+	first, err := decoder.Decode()
+	require.NoError(t, err)
+
+	_, err = decoder.Decode()
+	require.NoError(t, err)
+
+	// first.Data must not have been overwritten by the second Decode call.
+	require.Equal(t, "one", string(first.Data))
+}
@@ -2,7 +2,6 @@ package renderer
 
 import (
 	"bytes"
-	"io"
 	"strings"
 	"testing"
 
@@ -13,7 +12,7 @@ func TestSSEventEncode_FullEvent(t *testing.T) {
 	var buf bytes.Buffer
 	event := SSEvent{
 		Event: "message",
-		Data:  strings.NewReader("hello world"),
+		Data:  []byte("hello world"),
 		ID:    "123",
 		Retry: 3000,
 	}
@@ -31,7 +30,7 @@ func TestSSEventEncode_FullEvent(t *testing.T) {
 func TestSSEventEncode_MinimalEvent(t *testing.T) {
 	var buf bytes.Buffer
 	event := SSEvent{
-		Data: strings.NewReader("test data"),
+		Data: []byte("test data"),
 	}
 
 	err := SSEventEncode(&buf, event)
@@ -45,7 +44,7 @@ func TestSSEventEncode_OnlyEventName(t *testing.T) {
 	var buf bytes.Buffer
 	event := SSEvent{
 		Event: "custom-event",
-		Data:  strings.NewReader("some data"),
+		Data:  []byte("some data"),
 	}
 
 	err := SSEventEncode(&buf, event)
@@ -59,7 +58,7 @@ func TestSSEventEncode_OnlyEventName(t *testing.T) {
 func TestSSEventEncode_WithID(t *testing.T) {
 	var buf bytes.Buffer
 	event := SSEvent{
-		Data: strings.NewReader("data with id"),
+		Data: []byte("data with id"),
 		ID:   "event-456",
 	}
 
@@ -74,7 +73,7 @@ func TestSSEventEncode_WithID(t *testing.T) {
 func TestSSEventEncode_WithRetry(t *testing.T) {
 	var buf bytes.Buffer
 	event := SSEvent{
-		Data:  strings.NewReader("retry data"),
+		Data:  []byte("retry data"),
 		Retry: 5000,
 	}
 
@@ -90,7 +89,7 @@ func TestSSEventEncode_MultilineData(t *testing.T) {
 	var buf bytes.Buffer
 	event := SSEvent{
 		Event: "multiline",
-		Data:  strings.NewReader("line1\nline2\nline3"),
+		Data:  []byte("line1\nline2\nline3"),
 	}
 
 	err := SSEventEncode(&buf, event)
@@ -98,7 +97,50 @@ func TestSSEventEncode_MultilineData(t *testing.T) {
 
 	result := buf.String()
 	require.Contains(t, result, "event: multiline\n")
-	require.Contains(t, result, "data: line1\nline2\nline3\n\n")
+	require.Contains(t, result, "data: line1\ndata: line2\ndata: line3\n\n")
+}
+
+func TestSSEventEncode_NormalizesCROnlyAndCRLFInData(t *testing.T) {
+	var buf bytes.Buffer
+	event := SSEvent{Data: []byte("line1\rline2\r\nline3")}
+
+	err := SSEventEncode(&buf, event)
+	require.NoError(t, err)
+	require.Equal(t, "data: line1\ndata: line2\ndata: line3\n\n", buf.String())
+}
+
+func TestSSEventEncode_RejectsNewlineInEvent(t *testing.T) {
+	var buf bytes.Buffer
+	event := SSEvent{Event: "bad\nevent", Data: []byte("data")}
+
+	err := SSEventEncode(&buf, event)
+	require.Error(t, err)
+	var encodeErr *SSEEncodeError
+	require.ErrorAs(t, err, &encodeErr)
+	require.Equal(t, "event", encodeErr.Field)
+}
+
+func TestSSEventEncode_RejectsNullInID(t *testing.T) {
+	var buf bytes.Buffer
+	event := SSEvent{ID: "bad\x00id", Data: []byte("data")}
+
+	err := SSEventEncode(&buf, event)
+	require.Error(t, err)
+	var encodeErr *SSEEncodeError
+	require.ErrorAs(t, err, &encodeErr)
+	require.Equal(t, "id", encodeErr.Field)
+}
+
+func TestSSEventEncodeDecode_ArbitraryMultilinePayloadRoundTrips(t *testing.T) {
+	original := SSEvent{Event: "log", Data: []byte("line1\nline2\r\nline3\rline4")}
+
+	var buf bytes.Buffer
+	require.NoError(t, SSEventEncode(&buf, original))
+
+	events, err := SSEventDecode(&buf)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "line1\nline2\nline3\nline4", string(events[0].Data))
 }
 
 func TestSSEventDecode_SingleEvent(t *testing.T) {
@@ -111,10 +153,7 @@ func TestSSEventDecode_SingleEvent(t *testing.T) {
 	require.Equal(t, "message", event.Event)
 	require.Equal(t, "123", event.ID)
 	require.Equal(t, uint(3000), event.Retry)
-
-	data, err := io.ReadAll(event.Data)
-	require.NoError(t, err)
-	require.Equal(t, "hello world", string(data))
+	require.Equal(t, "hello world", string(event.Data))
 }
 
 func TestSSEventDecode_MultipleEvents(t *testing.T) {
@@ -124,14 +163,10 @@ func TestSSEventDecode_MultipleEvents(t *testing.T) {
 	require.Len(t, events, 2)
 
 	require.Equal(t, "msg1", events[0].Event)
-	data1, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, "first", string(data1))
+	require.Equal(t, "first", string(events[0].Data))
 
 	require.Equal(t, "msg2", events[1].Event)
-	data2, err := io.ReadAll(events[1].Data)
-	require.NoError(t, err)
-	require.Equal(t, "second", string(data2))
+	require.Equal(t, "second", string(events[1].Data))
 }
 
 func TestSSEventDecode_DefaultEventType(t *testing.T) {
@@ -147,10 +182,7 @@ func TestSSEventDecode_MultilineData(t *testing.T) {
 	events, err := SSEventDecode(strings.NewReader(input))
 	require.NoError(t, err)
 	require.Len(t, events, 1)
-
-	data, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, "line1\nline2\nline3", string(data))
+	require.Equal(t, "line1\nline2\nline3", string(events[0].Data))
 }
 
 func TestSSEventDecode_CommentsIgnored(t *testing.T) {
@@ -174,10 +206,7 @@ func TestSSEventDecode_OnlyData(t *testing.T) {
 	events, err := SSEventDecode(strings.NewReader(input))
 	require.NoError(t, err)
 	require.Len(t, events, 1)
-
-	data, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, "simple message", string(data))
+	require.Equal(t, "simple message", string(events[0].Data))
 }
 
 func TestSSEventDecode_InvalidRetry(t *testing.T) {
@@ -198,7 +227,7 @@ func TestSSEventDecode_FieldWithoutColon(t *testing.T) {
 func TestSSEventEncodeDecode_RoundTrip(t *testing.T) {
 	original := SSEvent{
 		Event: "test-event",
-		Data:  strings.NewReader("test data"),
+		Data:  []byte("test data"),
 		ID:    "test-id",
 		Retry: 1000,
 	}
@@ -215,27 +244,24 @@ func TestSSEventEncodeDecode_RoundTrip(t *testing.T) {
 	require.Equal(t, original.Event, decoded.Event)
 	require.Equal(t, original.ID, decoded.ID)
 	require.Equal(t, original.Retry, decoded.Retry)
-
-	data, err := io.ReadAll(decoded.Data)
-	require.NoError(t, err)
-	require.Equal(t, "test data", string(data))
+	require.Equal(t, "test data", string(decoded.Data))
 }
 
 func TestSSEventEncodeDecode_MultipleEventsRoundTrip(t *testing.T) {
 	events := []SSEvent{
 		{
 			Event: "event1",
-			Data:  strings.NewReader("data1"),
+			Data:  []byte("data1"),
 			ID:    "1",
 		},
 		{
 			Event: "event2",
-			Data:  strings.NewReader("data2"),
+			Data:  []byte("data2"),
 			ID:    "2",
 		},
 		{
 			Event: "event3",
-			Data:  strings.NewReader("data3"),
+			Data:  []byte("data3"),
 			ID:    "3",
 		},
 	}
@@ -262,10 +288,7 @@ func TestSSEventDecode_BOMHandling(t *testing.T) {
 	events, err := SSEventDecode(strings.NewReader(input))
 	require.NoError(t, err)
 	require.Len(t, events, 1)
-
-	data, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, "test with BOM", string(data))
+	require.Equal(t, "test with BOM", string(events[0].Data))
 }
 
 func TestSSEventDecode_CRLFLineEndings(t *testing.T) {
@@ -276,9 +299,7 @@ func TestSSEventDecode_CRLFLineEndings(t *testing.T) {
 	require.Len(t, events, 1)
 
 	require.Equal(t, "test", events[0].Event)
-	data, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, "line1\nline2", string(data))
+	require.Equal(t, "line1\nline2", string(events[0].Data))
 }
 
 func TestSSEventDecode_CRLineEndings(t *testing.T) {
@@ -289,9 +310,7 @@ func TestSSEventDecode_CRLineEndings(t *testing.T) {
 	require.Len(t, events, 1)
 
 	require.Equal(t, "test", events[0].Event)
-	data, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, "single CR", string(data))
+	require.Equal(t, "single CR", string(events[0].Data))
 }
 
 func TestSSEventDecode_IDWithNull(t *testing.T) {
@@ -337,10 +356,7 @@ func TestSSEventDecode_MixedLineEndings(t *testing.T) {
 	events, err := SSEventDecode(strings.NewReader(input))
 	require.NoError(t, err)
 	require.Len(t, events, 1)
-
-	data, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, "line1\nline2\nline3", string(data))
+	require.Equal(t, "line1\nline2\nline3", string(events[0].Data))
 }
 
 func TestSSEventDecode_DataFieldWithLeadingSpace(t *testing.T) {
@@ -349,10 +365,7 @@ func TestSSEventDecode_DataFieldWithLeadingSpace(t *testing.T) {
 	events, err := SSEventDecode(strings.NewReader(input))
 	require.NoError(t, err)
 	require.Len(t, events, 1)
-
-	data, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, "with space\nwithout space", string(data))
+	require.Equal(t, "with space\nwithout space", string(events[0].Data))
 }
 
 func TestSSEventDecode_DataFieldWithMultipleLeadingSpaces(t *testing.T) {
@@ -361,10 +374,7 @@ func TestSSEventDecode_DataFieldWithMultipleLeadingSpaces(t *testing.T) {
 	events, err := SSEventDecode(strings.NewReader(input))
 	require.NoError(t, err)
 	require.Len(t, events, 1)
-
-	data, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, " two spaces", string(data))
+	require.Equal(t, " two spaces", string(events[0].Data))
 }
 
 func TestSSEventDecode_EmptyDataField(t *testing.T) {
@@ -373,8 +383,5 @@ func TestSSEventDecode_EmptyDataField(t *testing.T) {
 	events, err := SSEventDecode(strings.NewReader(input))
 	require.NoError(t, err)
 	require.Len(t, events, 1)
-
-	data, err := io.ReadAll(events[0].Data)
-	require.NoError(t, err)
-	require.Equal(t, "", string(data))
+	require.Equal(t, "", string(events[0].Data))
 }
@@ -0,0 +1,44 @@
+package renderer
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SSEventWriter writes spec-conformant SSE frames to an underlying
+// io.Writer and flushes after every write, so events and heartbeats reach
+// the client as soon as they're written instead of sitting buffered.
+type SSEventWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+}
+
+// NewSSEventWriter returns a SSEventWriter that writes to w, flushing via
+// flusher after every event or comment.
+func NewSSEventWriter(w io.Writer, flusher http.Flusher) *SSEventWriter {
+	return &SSEventWriter{w: w, flusher: flusher}
+}
+
+// WriteEvent encodes and writes event, then flushes.
+func (sw *SSEventWriter) WriteEvent(event SSEvent) error {
+	if err := SSEventEncode(sw.w, event); err != nil {
+		return err
+	}
+	sw.flusher.Flush()
+	return nil
+}
+
+// WriteComment writes a comment frame, such as a heartbeat ping, and
+// flushes. text must not contain \r or \n.
+func (sw *SSEventWriter) WriteComment(text string) error {
+	if strings.ContainsAny(text, "\r\n") {
+		return &SSEEncodeError{Field: "comment", Value: text}
+	}
+	if _, err := fmt.Fprintf(sw.w, ": %s\n\n", text); err != nil {
+		return err
+	}
+	sw.flusher.Flush()
+	return nil
+}
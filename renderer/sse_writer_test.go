@@ -0,0 +1,39 @@
+package renderer
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEventWriter_WriteEvent(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sw := NewSSEventWriter(rec, rec)
+
+	err := sw.WriteEvent(SSEvent{Event: "test", Data: []byte("hello")})
+	require.NoError(t, err)
+	require.Equal(t, "event: test\ndata: hello\n\n", rec.Body.String())
+	require.True(t, rec.Flushed)
+}
+
+func TestSSEventWriter_WriteComment(t *testing.T) {
+	var buf bytes.Buffer
+	rec := httptest.NewRecorder()
+	sw := NewSSEventWriter(&buf, rec)
+
+	err := sw.WriteComment("ping")
+	require.NoError(t, err)
+	require.Equal(t, ": ping\n\n", buf.String())
+}
+
+func TestSSEventWriter_WriteCommentRejectsNewline(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sw := NewSSEventWriter(rec, rec)
+
+	err := sw.WriteComment("bad\ncomment")
+	require.Error(t, err)
+	var encodeErr *SSEEncodeError
+	require.ErrorAs(t, err, &encodeErr)
+}